@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+// OAuthProviderConfig holds the client credentials and endpoints needed to
+// drive an OAuth2/OIDC authorization code flow for a single provider.
+type OAuthProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+	Scopes       []string
+}
+
+// SMTPConfig holds the outbound mail settings used by SMTPMailer.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type Config struct {
+	MongoURI       string
+	MongoDB        string
+	OAuthProviders map[string]OAuthProviderConfig
+
+	// AppBaseURL prefixes the verify/reset links mailed to users.
+	AppBaseURL string
+	// RequireVerifiedEmail gates loginHandler on User.EmailVerified for
+	// password accounts.
+	RequireVerifiedEmail bool
+	SMTP                 SMTPConfig
+}
+
+func loadConfig() Config {
+	requireVerifiedEmail, _ := strconv.ParseBool(getEnv("REQUIRE_VERIFIED_EMAIL", "false"))
+
+	return Config{
+		MongoURI:             getEnv("MONGO_URI", "mongodb://localhost:27017"),
+		MongoDB:              getEnv("MONGO_DB", "vulnora"),
+		AppBaseURL:           getEnv("APP_BASE_URL", "http://localhost:3000"),
+		RequireVerifiedEmail: requireVerifiedEmail,
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "no-reply@vulnora.io"),
+		},
+		OAuthProviders: map[string]OAuthProviderConfig{
+			"google": {
+				ClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
+				ClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
+				RedirectURL:  getEnv("GOOGLE_REDIRECT_URL", ""),
+				AuthURL:      "https://accounts.google.com/o/oauth2/v2/auth",
+				TokenURL:     "https://oauth2.googleapis.com/token",
+				UserInfoURL:  "https://openidconnect.googleapis.com/v1/userinfo",
+				Scopes:       []string{"openid", "email", "profile"},
+			},
+			"github": {
+				ClientID:     getEnv("GITHUB_CLIENT_ID", ""),
+				ClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
+				RedirectURL:  getEnv("GITHUB_REDIRECT_URL", ""),
+				AuthURL:      "https://github.com/login/oauth/authorize",
+				TokenURL:     "https://github.com/login/oauth/access_token",
+				UserInfoURL:  "https://api.github.com/user",
+				Scopes:       []string{"read:user", "user:email"},
+			},
+		},
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+var appConfig = loadConfig()
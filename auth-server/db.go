@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	mongoClient       *mongo.Client
+	usersColl         *mongo.Collection
+	reportsColl       *mongo.Collection
+	refreshTokensColl *mongo.Collection
+	authTokensColl    *mongo.Collection
+	loginAttemptsColl *mongo.Collection
+)
+
+func connectMongo(uri, dbName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return err
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		return err
+	}
+
+	mongoClient = client
+	db := client.Database(dbName)
+	usersColl = db.Collection("users")
+	reportsColl = db.Collection("reports")
+	refreshTokensColl = db.Collection("refresh_tokens")
+	authTokensColl = db.Collection("auth_tokens")
+	loginAttemptsColl = db.Collection("login_attempts")
+
+	if err := ensureIndexes(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ensureIndexes applies the index migrations the users, reports,
+// refresh_tokens, auth_tokens and login_attempts collections depend on.
+// Mongo's createIndexes is idempotent, so this is safe to run on every boot.
+func ensureIndexes(ctx context.Context) error {
+	_, err := usersColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "username", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = refreshTokensColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "jti", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Backs getReportsHandler's per-user paged/sorted listing.
+	_, err = reportsColl.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = authTokensColl.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = loginAttemptsColl.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "email", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	return err
+}
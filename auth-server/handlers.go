@@ -2,14 +2,16 @@ package main
 
 import (
 	"context"
+	"log"
 	"net/http"
+	"strings"
 	"time"
-	"log"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -23,21 +25,29 @@ func signupHandler(c *gin.Context) {
 		return
 	}
 
-	// Check if user already exists
 	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
 	defer cancel()
 
+	// Username isn't sensitive the way email is, so a taken username can be
+	// reported directly; a taken email falls through to the same generic
+	// response as success so signup can't be used to enumerate accounts.
 	var existingUser User
-	err := usersColl.FindOne(ctx, bson.M{"email": req.Email}).Decode(&existingUser)
+	err := usersColl.FindOne(ctx, bson.M{"username": req.Username}).Decode(&existingUser)
 	if err == nil {
 		c.JSON(http.StatusConflict, ErrorResponse{
 			Error:   "user_exists",
-			Message: "User with this email already exists",
+			Message: "This username is already taken",
 		})
 		return
 	}
 
-	// Hash Password
+	genericResponse := SuccessResponse{
+		Message: "If this email can be used to register, you'll receive a confirmation email shortly",
+	}
+
+	// Hash the password before checking whether the email is taken, not
+	// after, so both branches pay bcrypt's cost and the existing-email
+	// response can't be timed apart from a real signup.
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -47,12 +57,20 @@ func signupHandler(c *gin.Context) {
 		return
 	}
 
+	err = usersColl.FindOne(ctx, bson.M{"email": req.Email}).Decode(&existingUser)
+	if err == nil {
+		c.JSON(http.StatusAccepted, genericResponse)
+		return
+	}
+
 	// Create New User
 	user := User{
 		Email:     req.Email,
+		Username:  req.Username,
 		Password:  string(hashedPassword),
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
+		AuthType:  AuthTypePassword,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -68,20 +86,14 @@ func signupHandler(c *gin.Context) {
 
 	user.ID = result.InsertedID.(primitive.ObjectID)
 
-	// Generate JWT Token
-	token, err := generateJWT(user.ID.Hex())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "token_error",
-			Message: "Failed to generate authentication token",
-		})
-		return
+	if token, err := createAuthToken(ctx, user.ID.Hex(), AuthTokenPurposeVerifyEmail, verifyTokenTTL); err == nil {
+		link := appConfig.AppBaseURL + "/auth/verify/confirm?token=" + token
+		mailer.SendVerificationEmail(user.Email, link)
+	} else {
+		log.Println("[ERROR] Failed to create verification token:", err)
 	}
 
-	c.JSON(http.StatusCreated, LoginResponse{
-		User:  user,
-		Token: token,
-	})
+	c.JSON(http.StatusAccepted, genericResponse)
 }
 
 func loginHandler(c *gin.Context) {
@@ -94,17 +106,43 @@ func loginHandler(c *gin.Context) {
 		return
 	}
 
-	// Find user by email
+	// Find user by email or username
 	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
 	defer cancel()
 
 	var user User
-	err := usersColl.FindOne(ctx, bson.M{"email": req.Email}).Decode(&user)
+	err := usersColl.FindOne(ctx, bson.M{"$or": []bson.M{
+		{"email": req.Identifier},
+		{"username": req.Identifier},
+	}}).Decode(&user)
+
+	// Lock out by the resolved account, not the raw identifier: since login
+	// accepts either email or username, keying on the request field would
+	// let an attacker split attempts across both and get two lockout
+	// budgets for the same account. Fall back to the normalized identifier
+	// when it doesn't resolve to anyone.
+	lockKey := strings.ToLower(req.Identifier)
+	if err == nil {
+		lockKey = user.ID.Hex()
+	}
+
+	if locked, retryAfter := checkAccountLocked(lockKey); locked {
+		recordLoginAttempt(c, req.Identifier, false)
+		c.Header("Retry-After", retryAfterSeconds(retryAfter))
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error:   "account_locked",
+			Message: "Too many failed login attempts, please try again later",
+		})
+		return
+	}
+
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
+			recordFailedLogin(lockKey)
+			recordLoginAttempt(c, req.Identifier, false)
 			c.JSON(http.StatusUnauthorized, ErrorResponse{
 				Error:   "invalid_credentials",
-				Message: "Invalid email or password",
+				Message: "Invalid credentials",
 			})
 			return
 		}
@@ -117,15 +155,28 @@ func loginHandler(c *gin.Context) {
 
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		recordFailedLogin(lockKey)
+		recordLoginAttempt(c, req.Identifier, false)
 		c.JSON(http.StatusUnauthorized, ErrorResponse{
 			Error:   "invalid_credentials",
-			Message: "Invalid email or password",
+			Message: "Invalid credentials",
+		})
+		return
+	}
+
+	resetFailedLogins(lockKey)
+	recordLoginAttempt(c, req.Identifier, true)
+
+	if appConfig.RequireVerifiedEmail && user.AuthType == AuthTypePassword && !user.EmailVerified {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "email_not_verified",
+			Message: "Please verify your email before logging in",
 		})
 		return
 	}
 
-	// Generate JWT token
-	token, err := generateJWT(user.ID.Hex())
+	// Issue access + refresh tokens
+	resp, err := issueTokenPair(ctx, user.ID.Hex(), c.GetHeader("User-Agent"), c.ClientIP())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "token_error",
@@ -133,24 +184,37 @@ func loginHandler(c *gin.Context) {
 		})
 		return
 	}
+	resp.User = user
 
-	c.JSON(http.StatusOK, LoginResponse{
-		User:  user,
-		Token: token,
-	})
+	c.JSON(http.StatusOK, resp)
 }
 
-func getReportsHandler(c *gin.Context) {	
+func getReportsHandler(c *gin.Context) {
 	userID := c.GetString("userID")
 	log.Println("[DEBUG] userID from middleware:", userID)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
 	defer cancel()
 
+	params := parseReportsListParams(c)
+	filter := params.filter(userID)
+
+	total, err := reportsColl.CountDocuments(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to count user reports",
+		})
+		return
+	}
+
 	var reports []Report
-	filter := bson.M{"user_id": userID}
+	findOpts := options.Find().
+		SetSort(params.sort).
+		SetSkip(int64((params.page - 1) * params.limit)).
+		SetLimit(int64(params.limit))
 
-	cursor, err := reportsColl.Find(ctx, filter)
+	cursor, err := reportsColl.Find(ctx, filter, findOpts)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			c.JSON(http.StatusNotFound, ErrorResponse{
@@ -188,9 +252,20 @@ func getReportsHandler(c *gin.Context) {
 		return
 	}
 
+	totalPages := int(total) / params.limit
+	if int(total)%params.limit != 0 {
+		totalPages++
+	}
+
 	c.JSON(http.StatusOK, SuccessResponse{
 		Message: "Reports fetched successfully",
-		Data:    reports,
+		Data: PaginatedReportsResponse{
+			Data:       reports,
+			Page:       params.page,
+			Limit:      params.limit,
+			Total:      total,
+			TotalPages: totalPages,
+		},
 	})
 }
 
@@ -244,7 +319,7 @@ func getUserInfoHandler(c *gin.Context) {
 		"email":         user.Email,
 		"first_name":    user.FirstName,
 		"last_name":     user.LastName,
-		"username":      user.FirstName + " " + user.LastName,
+		"username":      user.Username,
 		"attacks_count": reportsCount,
 		"created_at":    user.CreatedAt,
 	}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var jwtSecret = []byte(getEnv("JWT_SECRET", "dev-secret-change-me"))
+
+const accessTokenTTL = 15 * time.Minute
+
+// revokedJTIs is a short-lived in-memory blacklist of access tokens revoked
+// by logout. It only needs to hold entries up to accessTokenTTL, since a
+// token older than that is already rejected on expiry.
+var revokedJTIs = struct {
+	sync.Mutex
+	m map[string]time.Time
+}{m: make(map[string]time.Time)}
+
+func revokeJTI(jti string, expiresAt time.Time) {
+	revokedJTIs.Lock()
+	defer revokedJTIs.Unlock()
+
+	// Any access token still live now expires within accessTokenTTL, so
+	// there is no point keeping the entry longer than that.
+	if limit := time.Now().Add(accessTokenTTL); expiresAt.After(limit) {
+		expiresAt = limit
+	}
+	revokedJTIs.m[jti] = expiresAt
+	for id, exp := range revokedJTIs.m {
+		if time.Now().After(exp) {
+			delete(revokedJTIs.m, id)
+		}
+	}
+}
+
+func isJTIRevoked(jti string) bool {
+	revokedJTIs.Lock()
+	defer revokedJTIs.Unlock()
+
+	exp, ok := revokedJTIs.m[jti]
+	return ok && time.Now().Before(exp)
+}
+
+// generateJWT issues an access token with a random jti so it can be
+// individually revoked on logout. It returns the signed token alongside
+// the jti and expiry so callers can also set up the refresh-token record.
+func generateJWT(userID string) (token string, jti string, expiresAt time.Time, err error) {
+	jti = randomHex(16)
+	expiresAt = time.Now().Add(accessTokenTTL)
+
+	claims := jwt.MapClaims{
+		"sub": userID,
+		"jti": jti,
+		"iat": time.Now().Unix(),
+		"exp": expiresAt.Unix(),
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+	return signed, jti, expiresAt, err
+}
+
+// jwtMiddleware validates the Bearer token and sets "userID" and "jti" in
+// the context for downstream handlers (getReportsHandler, getUserInfoHandler,
+// logoutHandler).
+func jwtMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "unauthorized",
+				Message: "Missing or malformed authorization header",
+			})
+			return
+		}
+
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(parts[1], claims, func(t *jwt.Token) (interface{}, error) {
+			return jwtSecret, nil
+		})
+		if err != nil || !token.Valid {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "unauthorized",
+				Message: "Invalid or expired token",
+			})
+			return
+		}
+
+		jti, _ := claims["jti"].(string)
+		if jti != "" && isJTIRevoked(jti) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "unauthorized",
+				Message: "Token has been revoked",
+			})
+			return
+		}
+
+		sub, _ := claims["sub"].(string)
+		c.Set("userID", sub)
+		c.Set("jti", jti)
+		c.Next()
+	}
+}
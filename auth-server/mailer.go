@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+)
+
+// Mailer is the pluggable extension point for outbound transactional email.
+// SMTPMailer is used in production; devMailer just logs the link so local
+// development doesn't need a real mail server.
+type Mailer interface {
+	SendVerificationEmail(to, link string) error
+	SendPasswordResetEmail(to, link string) error
+}
+
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+func (m SMTPMailer) send(to, subject, body string) error {
+	addr := m.cfg.Host + ":" + m.cfg.Port
+	auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n", m.cfg.From, to, subject, body)
+	return smtp.SendMail(addr, auth, m.cfg.From, []string{to}, []byte(msg))
+}
+
+func (m SMTPMailer) SendVerificationEmail(to, link string) error {
+	return m.send(to, "Verify your Vulnora email", "Confirm your email: "+link)
+}
+
+func (m SMTPMailer) SendPasswordResetEmail(to, link string) error {
+	return m.send(to, "Reset your Vulnora password", "Reset your password: "+link)
+}
+
+// devMailer logs the link instead of sending mail, for local development
+// where no SMTP server is configured.
+type devMailer struct{}
+
+func (devMailer) SendVerificationEmail(to, link string) error {
+	log.Printf("[DEV MAILER] verification link for %s: %s", to, link)
+	return nil
+}
+
+func (devMailer) SendPasswordResetEmail(to, link string) error {
+	log.Printf("[DEV MAILER] password reset link for %s: %s", to, link)
+	return nil
+}
+
+func newMailer(cfg SMTPConfig) Mailer {
+	if cfg.Host == "" {
+		return devMailer{}
+	}
+	return SMTPMailer{cfg: cfg}
+}
+
+var mailer = newMailer(appConfig.SMTP)
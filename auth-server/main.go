@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+func main() {
+	if err := connectMongo(appConfig.MongoURI, appConfig.MongoDB); err != nil {
+		log.Fatalf("failed to connect to mongo: %v", err)
+	}
+
+	// Brute-force protection: a handful of requests per minute per IP is
+	// plenty for a real user, and slows down credential-stuffing scripts.
+	bruteForceGuard := ipRateLimitMiddleware(rate.Every(6*time.Second), 5)
+
+	router := gin.Default()
+
+	auth := router.Group("/auth")
+	auth.POST("/signup", bruteForceGuard, signupHandler)
+	auth.POST("/login", bruteForceGuard, loginHandler)
+	auth.POST("/refresh", refreshHandler)
+	auth.GET("/oauth/:provider/login", oauthLoginHandler)
+	auth.GET("/oauth/:provider/callback", oauthCallbackHandler)
+	auth.POST("/verify/request", requestVerificationHandler)
+	auth.GET("/verify/confirm", confirmVerificationHandler)
+	auth.POST("/password/forgot", bruteForceGuard, forgotPasswordHandler)
+	auth.POST("/password/reset", bruteForceGuard, resetPasswordHandler)
+
+	authed := auth.Group("/")
+	authed.Use(jwtMiddleware())
+	authed.POST("logout", logoutHandler)
+	authed.POST("logout-all", logoutAllHandler)
+	authed.GET("sessions", listSessionsHandler)
+
+	api := router.Group("/")
+	api.Use(jwtMiddleware())
+	api.GET("reports", getReportsHandler)
+	api.GET("user/info", getUserInfoHandler)
+
+	router.Run(getEnv("LISTEN_ADDR", ":8080"))
+}
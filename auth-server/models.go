@@ -0,0 +1,142 @@
+package main
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AuthType identifies which AuthProvider created/owns a user's credentials.
+type AuthType string
+
+const (
+	AuthTypePassword AuthType = "password"
+	AuthTypeOAuth    AuthType = "oauth"
+)
+
+type User struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email     string             `bson:"email" json:"email"`
+	Username  string             `bson:"username" json:"username"`
+	Password  string             `bson:"password,omitempty" json:"-"`
+	FirstName string             `bson:"first_name" json:"first_name"`
+	LastName  string             `bson:"last_name" json:"last_name"`
+
+	// AuthType records which provider owns this account's credentials.
+	// ProviderSubject is the upstream provider's stable subject ("sub") for
+	// OAuth-linked accounts, including password accounts later linked to a
+	// provider, and is empty for accounts that never signed in via OAuth.
+	AuthType        AuthType `bson:"auth_type" json:"auth_type"`
+	Provider        string   `bson:"provider,omitempty" json:"provider,omitempty"`
+	ProviderSubject string   `bson:"provider_subject,omitempty" json:"-"`
+
+	EmailVerified bool `bson:"email_verified" json:"email_verified"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+type SignupRequest struct {
+	Email     string `json:"email" binding:"required,email"`
+	Username  string `json:"username" binding:"required,min=3,max=32"`
+	Password  string `json:"password" binding:"required,min=8"`
+	FirstName string `json:"first_name" binding:"required"`
+	LastName  string `json:"last_name" binding:"required"`
+}
+
+type LoginRequest struct {
+	Identifier string `json:"identifier" binding:"required"`
+	Password   string `json:"password" binding:"required"`
+}
+
+type LoginResponse struct {
+	User         User   `json:"user"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// RefreshToken is a single issued refresh token. Only TokenHash is stored,
+// never the raw token, so a database leak alone can't be used to mint
+// access tokens.
+type RefreshToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	JTI       string             `bson:"jti" json:"jti"`
+	TokenHash string             `bson:"token_hash" json:"-"`
+	UserAgent string             `bson:"user_agent" json:"user_agent"`
+	IP        string             `bson:"ip" json:"ip"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	RevokedAt *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// AuthTokenPurpose distinguishes the single-use tokens stored in the
+// auth_tokens collection so the same collection can back both flows.
+type AuthTokenPurpose string
+
+const (
+	AuthTokenPurposeVerifyEmail   AuthTokenPurpose = "verify_email"
+	AuthTokenPurposePasswordReset AuthTokenPurpose = "password_reset"
+)
+
+// AuthToken is a single-use, short-lived token for email verification or
+// password reset. Only TokenHash is stored; the raw token is mailed to the
+// user and never persisted.
+type AuthToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"user_id" json:"user_id"`
+	TokenHash string             `bson:"token_hash" json:"-"`
+	Purpose   AuthTokenPurpose   `bson:"purpose" json:"purpose"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time         `bson:"used_at,omitempty" json:"used_at,omitempty"`
+}
+
+type RequestVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+// LoginAttempt is an audit record of every login attempt, successful or
+// not, used to investigate brute-force activity.
+type LoginAttempt struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Email     string             `bson:"email" json:"email"`
+	IP        string             `bson:"ip" json:"ip"`
+	UserAgent string             `bson:"user_agent" json:"user_agent"`
+	Success   bool               `bson:"success" json:"success"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+type SuccessResponse struct {
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
+
+type Report struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID   string             `bson:"user_id" json:"user_id"`
+	Severity string             `bson:"severity" json:"severity"`
+	Title    string             `bson:"title" json:"title"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
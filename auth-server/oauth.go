@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AuthProvider is implemented by every way a user can authenticate.
+// PasswordProvider names the bcrypt email/password flow;
+// OAuthProvider backs federated sign-in via an external IdP.
+type AuthProvider interface {
+	Name() string
+}
+
+// PasswordProvider identifies the bcrypt email/password flow by name only.
+// It has no signup or login methods: those flows are handled by
+// signupHandler and loginHandler directly.
+type PasswordProvider struct{}
+
+func (PasswordProvider) Name() string { return string(AuthTypePassword) }
+
+// OAuthProvider drives the authorization-code flow for a single configured
+// provider (google, github, ...) and links or creates the local User on
+// callback.
+type OAuthProvider struct {
+	provider string
+	cfg      OAuthProviderConfig
+}
+
+func (p OAuthProvider) Name() string { return p.provider }
+
+func newOAuthProvider(name string) (*OAuthProvider, bool) {
+	cfg, ok := appConfig.OAuthProviders[name]
+	if !ok {
+		return nil, false
+	}
+	return &OAuthProvider{provider: name, cfg: cfg}, true
+}
+
+// oauthStates tracks in-flight authorization requests so the callback can be
+// matched back to the state value we handed the provider. A production
+// deployment would back this with Redis; an in-memory store is fine for a
+// single auth-server instance.
+var oauthStates = struct {
+	sync.Mutex
+	m map[string]time.Time
+}{m: make(map[string]time.Time)}
+
+func newOAuthState() string {
+	state := randomHex(16)
+
+	oauthStates.Lock()
+	oauthStates.m[state] = time.Now().Add(10 * time.Minute)
+	oauthStates.Unlock()
+	return state
+}
+
+func consumeOAuthState(state string) bool {
+	oauthStates.Lock()
+	defer oauthStates.Unlock()
+
+	expiry, ok := oauthStates.m[state]
+	if !ok {
+		return false
+	}
+	delete(oauthStates.m, state)
+	return time.Now().Before(expiry)
+}
+
+func oauthLoginHandler(c *gin.Context) {
+	providerName := c.Param("provider")
+	provider, ok := newOAuthProvider(providerName)
+	if !ok {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "unknown_provider",
+			Message: "Unsupported OAuth provider",
+		})
+		return
+	}
+
+	state := newOAuthState()
+	authURL := provider.cfg.AuthURL + "?" + url.Values{
+		"client_id":     {provider.cfg.ClientID},
+		"redirect_uri":  {provider.cfg.RedirectURL},
+		"response_type": {"code"},
+		"scope":         {joinScopes(provider.cfg.Scopes)},
+		"state":         {state},
+	}.Encode()
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+func oauthCallbackHandler(c *gin.Context) {
+	providerName := c.Param("provider")
+	provider, ok := newOAuthProvider(providerName)
+	if !ok {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "unknown_provider",
+			Message: "Unsupported OAuth provider",
+		})
+		return
+	}
+
+	code := c.Query("code")
+	state := c.Query("state")
+	if code == "" || !consumeOAuthState(state) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_state",
+			Message: "Missing authorization code or invalid/expired state",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
+	defer cancel()
+
+	accessToken, err := provider.exchangeCode(ctx, code)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, ErrorResponse{
+			Error:   "oauth_exchange_failed",
+			Message: "Failed to exchange authorization code",
+		})
+		return
+	}
+
+	info, err := provider.fetchUserInfo(ctx, accessToken)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, ErrorResponse{
+			Error:   "oauth_userinfo_failed",
+			Message: "Failed to fetch user info",
+		})
+		return
+	}
+
+	user, err := linkOrCreateOAuthUser(ctx, provider.provider, info)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to link or create user",
+		})
+		return
+	}
+
+	resp, err := issueTokenPair(ctx, user.ID.Hex(), c.GetHeader("User-Agent"), c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "token_error",
+			Message: "Failed to generate authentication token",
+		})
+		return
+	}
+	resp.User = *user
+
+	c.JSON(http.StatusOK, resp)
+}
+
+type oauthUserInfo struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+}
+
+func (p OAuthProvider) exchangeCode(ctx context.Context, code string) (string, error) {
+	form := url.Values{
+		"client_id":     {p.cfg.ClientID},
+		"client_secret": {p.cfg.ClientSecret},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"grant_type":    {"authorization_code"},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token exchange failed with status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	return body.AccessToken, nil
+}
+
+func (p OAuthProvider) fetchUserInfo(ctx context.Context, accessToken string) (*oauthUserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.UserInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("userinfo request failed with status %d", resp.StatusCode)
+	}
+
+	var raw struct {
+		Sub           string `json:"sub"`
+		ID            int64  `json:"id"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		GivenName     string `json:"given_name"`
+		Name          string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	sub := raw.Sub
+	if sub == "" && raw.ID != 0 {
+		// GitHub's userinfo endpoint returns a numeric id, not a "sub".
+		sub = strconv.FormatInt(raw.ID, 10)
+	}
+
+	return &oauthUserInfo{
+		Subject:       sub,
+		Email:         raw.Email,
+		EmailVerified: raw.EmailVerified,
+		FirstName:     raw.GivenName,
+		LastName:      raw.Name,
+	}, nil
+}
+
+// linkOrCreateOAuthUser finds an existing account for this provider subject,
+// falls back to linking by email (promoting a password account to also
+// accept OAuth sign-in) when the provider vouches the email is verified, or
+// creates a brand new user.
+func linkOrCreateOAuthUser(ctx context.Context, provider string, info *oauthUserInfo) (*User, error) {
+	var user User
+
+	err := usersColl.FindOne(ctx, bson.M{
+		"provider":         provider,
+		"provider_subject": info.Subject,
+	}).Decode(&user)
+	if err == nil {
+		return &user, nil
+	}
+
+	// Linking by email only makes sense if the provider confirms the
+	// requester actually controls that address; otherwise anyone could
+	// take over an existing account by claiming its email unverified.
+	if info.EmailVerified {
+		err = usersColl.FindOne(ctx, bson.M{"email": info.Email}).Decode(&user)
+		if err == nil {
+			// Existing password account signing in with OAuth for the first
+			// time: link the provider subject without touching their password.
+			update := bson.M{"$set": bson.M{
+				"provider":         provider,
+				"provider_subject": info.Subject,
+				"updated_at":       time.Now(),
+			}}
+			if _, err := usersColl.UpdateOne(ctx, bson.M{"_id": user.ID}, update); err != nil {
+				return nil, err
+			}
+			user.Provider = provider
+			user.ProviderSubject = info.Subject
+			return &user, nil
+		}
+	}
+
+	username, err := generateUniqueUsername(ctx, info.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	newUser := User{
+		Email:           info.Email,
+		Username:        username,
+		FirstName:       info.FirstName,
+		LastName:        info.LastName,
+		AuthType:        AuthTypeOAuth,
+		Provider:        provider,
+		ProviderSubject: info.Subject,
+		EmailVerified:   info.EmailVerified,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	result, err := usersColl.InsertOne(ctx, newUser)
+	if err != nil {
+		return nil, err
+	}
+	newUser.ID = result.InsertedID.(primitive.ObjectID)
+	return &newUser, nil
+}
+
+var usernameSanitizer = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// generateUniqueUsername derives a username from the local part of an
+// OAuth-provided email (signup requires one, but OAuth providers don't hand
+// us one) and appends a random suffix until it clears the unique index,
+// retrying a bounded number of times rather than looping forever.
+func generateUniqueUsername(ctx context.Context, email string) (string, error) {
+	base := usernameSanitizer.ReplaceAllString(strings.ToLower(strings.SplitN(email, "@", 2)[0]), "")
+	if len(base) < 3 {
+		base = base + "user"
+	}
+	if len(base) > 24 {
+		base = base[:24]
+	}
+
+	for i := 0; i < 5; i++ {
+		candidate := base + "_" + randomHex(3)
+		var existing User
+		err := usersColl.FindOne(ctx, bson.M{"username": candidate}).Decode(&existing)
+		if err == mongo.ErrNoDocuments {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("could not generate a unique username")
+}
+
+func joinScopes(scopes []string) string {
+	out := ""
+	for i, s := range scopes {
+		if i > 0 {
+			out += " "
+		}
+		out += s
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultReportsPage  = 1
+	defaultReportsLimit = 20
+	maxReportsLimit     = 100
+)
+
+// PaginatedReportsResponse is the list-endpoint envelope shared by paged
+// report queries.
+type PaginatedReportsResponse struct {
+	Data       []Report `json:"data"`
+	Page       int      `json:"page"`
+	Limit      int      `json:"limit"`
+	Total      int64    `json:"total"`
+	TotalPages int      `json:"total_pages"`
+}
+
+// reportsListParams is the parsed, validated form of the getReportsHandler
+// query string: ?page=1&limit=20&sort=-created_at&severity=high&from=...&to=...
+type reportsListParams struct {
+	page     int
+	limit    int
+	sort     bson.D
+	severity string
+	from     *time.Time
+	to       *time.Time
+}
+
+func parseReportsListParams(c *gin.Context) reportsListParams {
+	params := reportsListParams{
+		page:     defaultReportsPage,
+		limit:    defaultReportsLimit,
+		sort:     bson.D{{Key: "created_at", Value: -1}},
+		severity: c.Query("severity"),
+	}
+
+	if v, err := strconv.Atoi(c.Query("page")); err == nil && v > 0 {
+		params.page = v
+	}
+
+	if v, err := strconv.Atoi(c.Query("limit")); err == nil && v > 0 {
+		params.limit = v
+	}
+	if params.limit > maxReportsLimit {
+		params.limit = maxReportsLimit
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		field := sort
+		order := 1
+		if strings.HasPrefix(sort, "-") {
+			field = sort[1:]
+			order = -1
+		}
+		params.sort = bson.D{{Key: field, Value: order}}
+	}
+
+	if from, err := time.Parse(time.RFC3339, c.Query("from")); err == nil {
+		params.from = &from
+	}
+	if to, err := time.Parse(time.RFC3339, c.Query("to")); err == nil {
+		params.to = &to
+	}
+
+	return params
+}
+
+func (p reportsListParams) filter(userID string) bson.M {
+	filter := bson.M{"user_id": userID}
+
+	if p.severity != "" {
+		filter["severity"] = p.severity
+	}
+
+	if p.from != nil || p.to != nil {
+		createdAt := bson.M{}
+		if p.from != nil {
+			createdAt["$gte"] = *p.from
+		}
+		if p.to != nil {
+			createdAt["$lte"] = *p.to
+		}
+		filter["created_at"] = createdAt
+	}
+
+	return filter
+}
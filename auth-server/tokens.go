@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueTokenPair generates a fresh access/refresh token pair for user and
+// persists the refresh token (hashed) so it can be looked up, rotated, or
+// revoked later.
+func issueTokenPair(ctx context.Context, userID, userAgent, ip string) (LoginResponse, error) {
+	accessToken, jti, expiresAt, err := generateJWT(userID)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	refreshToken := randomHex(32)
+	record := RefreshToken{
+		UserID:    userID,
+		JTI:       jti,
+		TokenHash: hashToken(refreshToken),
+		UserAgent: userAgent,
+		IP:        ip,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+	if _, err := refreshTokensColl.InsertOne(ctx, record); err != nil {
+		return LoginResponse{}, err
+	}
+
+	return LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(time.Until(expiresAt).Seconds()),
+	}, nil
+}
+
+func refreshHandler(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
+	defer cancel()
+
+	var record RefreshToken
+	err := refreshTokensColl.FindOne(ctx, bson.M{"token_hash": hashToken(req.RefreshToken)}).Decode(&record)
+	if err != nil || record.RevokedAt != nil || time.Now().After(record.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "invalid_refresh_token",
+			Message: "Refresh token is invalid, expired, or revoked",
+		})
+		return
+	}
+
+	// Rotate: revoke the presented token and mint a new pair.
+	now := time.Now()
+	if _, err := refreshTokensColl.UpdateOne(ctx,
+		bson.M{"_id": record.ID},
+		bson.M{"$set": bson.M{"revoked_at": now}},
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to rotate refresh token",
+		})
+		return
+	}
+	revokeJTI(record.JTI, record.ExpiresAt)
+
+	resp, err := issueTokenPair(ctx, record.UserID, c.GetHeader("User-Agent"), c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "token_error",
+			Message: "Failed to issue new tokens",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func logoutHandler(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
+	defer cancel()
+
+	var record RefreshToken
+	err := refreshTokensColl.FindOne(ctx, bson.M{"token_hash": hashToken(req.RefreshToken)}).Decode(&record)
+	if err == nil && record.RevokedAt == nil {
+		now := time.Now()
+		refreshTokensColl.UpdateOne(ctx,
+			bson.M{"_id": record.ID},
+			bson.M{"$set": bson.M{"revoked_at": now}},
+		)
+		revokeJTI(record.JTI, record.ExpiresAt)
+	}
+
+	if jti := c.GetString("jti"); jti != "" {
+		revokeJTI(jti, time.Now().Add(accessTokenTTL))
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Message: "Logged out successfully"})
+}
+
+// revokeAllSessions blacklists the jti of every active access token for
+// userID and revokes their refresh tokens. Used by logoutAllHandler and by
+// resetPasswordHandler, which needs the same "kick everyone out" behavior
+// once the password changes.
+func revokeAllSessions(ctx context.Context, userID string) error {
+	cursor, err := refreshTokensColl.Find(ctx, bson.M{"user_id": userID, "revoked_at": nil})
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	if cursor != nil {
+		defer cursor.Close(ctx)
+		for cursor.Next(ctx) {
+			var record RefreshToken
+			if err := cursor.Decode(&record); err == nil {
+				revokeJTI(record.JTI, record.ExpiresAt)
+			}
+		}
+	}
+
+	_, err = refreshTokensColl.UpdateMany(ctx,
+		bson.M{"user_id": userID, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	return err
+}
+
+func logoutAllHandler(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
+	defer cancel()
+
+	if err := revokeAllSessions(ctx, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to revoke sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Message: "All sessions logged out"})
+}
+
+func listSessionsHandler(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
+	defer cancel()
+
+	cursor, err := refreshTokensColl.Find(ctx, bson.M{
+		"user_id":    userID,
+		"revoked_at": nil,
+		"expires_at": bson.M{"$gt": time.Now()},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to list active sessions",
+		})
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []RefreshToken
+	if err := cursor.All(ctx, &sessions); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to decode sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "Active sessions fetched successfully",
+		Data:    sessions,
+	})
+}
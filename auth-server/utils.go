@@ -0,0 +1,19 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"time"
+)
+
+// randomHex returns a cryptographically random hex string of n random
+// bytes (2n hex characters). Falls back to a time-derived value if the
+// host's CSPRNG is broken, which should never happen in practice.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return hex.EncodeToString([]byte(time.Now().String()))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	verifyTokenTTL = 24 * time.Hour
+	resetTokenTTL  = 15 * time.Minute
+)
+
+// createAuthToken mints a random single-use token, stores its hash, and
+// returns the raw token for the caller to mail to the user.
+func createAuthToken(ctx context.Context, userID string, purpose AuthTokenPurpose, ttl time.Duration) (string, error) {
+	raw := randomHex(32)
+	record := AuthToken{
+		UserID:    userID,
+		TokenHash: hashToken(raw),
+		Purpose:   purpose,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if _, err := authTokensColl.InsertOne(ctx, record); err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// consumeAuthToken looks up an unused, unexpired token of the given purpose
+// and marks it used. Callers treat "not found" and "expired" identically so
+// as not to leak which is the case.
+func consumeAuthToken(ctx context.Context, raw string, purpose AuthTokenPurpose) (*AuthToken, error) {
+	var token AuthToken
+	err := authTokensColl.FindOne(ctx, bson.M{
+		"token_hash": hashToken(raw),
+		"purpose":    purpose,
+		"used_at":    nil,
+	}).Decode(&token)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, mongo.ErrNoDocuments
+	}
+
+	now := time.Now()
+	if _, err := authTokensColl.UpdateOne(ctx,
+		bson.M{"_id": token.ID},
+		bson.M{"$set": bson.M{"used_at": now}},
+	); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func requestVerificationHandler(c *gin.Context) {
+	var req RequestVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
+	defer cancel()
+
+	var user User
+	if err := usersColl.FindOne(ctx, bson.M{"email": req.Email}).Decode(&user); err == nil && !user.EmailVerified {
+		if token, err := createAuthToken(ctx, user.ID.Hex(), AuthTokenPurposeVerifyEmail, verifyTokenTTL); err == nil {
+			link := appConfig.AppBaseURL + "/auth/verify/confirm?token=" + token
+			mailer.SendVerificationEmail(user.Email, link)
+		}
+	}
+
+	// Always return the same response, verified or not, so this endpoint
+	// can't be used to enumerate registered emails.
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "If that email exists and is unverified, a verification link has been sent",
+	})
+}
+
+func confirmVerificationHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
+	defer cancel()
+
+	token, err := consumeAuthToken(ctx, c.Query("token"), AuthTokenPurposeVerifyEmail)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_token",
+			Message: "Verification token is invalid or expired",
+		})
+		return
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(token.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to verify email",
+		})
+		return
+	}
+
+	if _, err := usersColl.UpdateOne(ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{"email_verified": true, "updated_at": time.Now()}},
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to verify email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Message: "Email verified successfully"})
+}
+
+func forgotPasswordHandler(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
+	defer cancel()
+
+	var user User
+	if err := usersColl.FindOne(ctx, bson.M{"email": req.Email}).Decode(&user); err == nil {
+		if token, err := createAuthToken(ctx, user.ID.Hex(), AuthTokenPurposePasswordReset, resetTokenTTL); err == nil {
+			link := appConfig.AppBaseURL + "/auth/password/reset?token=" + token
+			mailer.SendPasswordResetEmail(user.Email, link)
+		}
+	}
+
+	// Same generic response whether or not the email exists, to prevent
+	// user enumeration via password reset.
+	c.JSON(http.StatusOK, SuccessResponse{
+		Message: "If that email exists, a password reset link has been sent",
+	})
+}
+
+func resetPasswordHandler(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 25*time.Second)
+	defer cancel()
+
+	token, err := consumeAuthToken(ctx, req.Token, AuthTokenPurposePasswordReset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_token",
+			Message: "Reset token is invalid or expired",
+		})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "server_error",
+			Message: "Failed to process password",
+		})
+		return
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(token.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to reset password",
+		})
+		return
+	}
+
+	if _, err := usersColl.UpdateOne(ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{"password": string(hashedPassword), "updated_at": time.Now()}},
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to reset password",
+		})
+		return
+	}
+
+	// A password reset is often recovery from a compromised account, so any
+	// session from before the reset - stolen refresh token or still-live
+	// access token - must stop working too.
+	if err := revokeAllSessions(ctx, token.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to revoke existing sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Message: "Password reset successfully"})
+}